@@ -0,0 +1,199 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/timtoronto634/doc-input-gen/format"
+)
+
+// regexListFlag collects repeated occurrences of a flag (e.g. multiple
+// --include REGEX) into a slice of compiled patterns.
+type regexListFlag struct {
+	patterns []*regexp.Regexp
+}
+
+func (r *regexListFlag) String() string {
+	if r == nil {
+		return ""
+	}
+	return fmt.Sprint(r.patterns)
+}
+
+func (r *regexListFlag) Set(value string) error {
+	re, err := regexp.Compile(value)
+	if err != nil {
+		return fmt.Errorf("invalid regex %q: %v", value, err)
+	}
+	r.patterns = append(r.patterns, re)
+	return nil
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	switch os.Args[1] {
+	case "generate":
+		if err := cmdGenerate(os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, "docgen generate:", err)
+			os.Exit(1)
+		}
+	case "wizard":
+		cmdWizard()
+	case "-h", "--help", "help":
+		usage()
+	default:
+		fmt.Fprintf(os.Stderr, "docgen: unknown command %q\n", os.Args[1])
+		usage()
+		os.Exit(2)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: docgen <generate|wizard> [flags]")
+}
+
+// cmdGenerate is the non-interactive entry point: flags in, a rendered
+// summary out, suitable for CI and shell pipelines.
+func cmdGenerate(args []string) error {
+	fs := flag.NewFlagSet("generate", flag.ContinueOnError)
+
+	root := fs.String("root", "", "root directory to summarize (default: current directory)")
+	var include, exclude regexListFlag
+	fs.Var(&include, "include", "only include paths matching this regex (repeatable)")
+	fs.Var(&exclude, "exclude", "exclude paths matching this regex, in addition to .gitignore (repeatable)")
+	patternsFile := fs.String("patterns-file", "", "file of newline-separated --include regexes")
+	output := fs.String("output", "", "output path, or - for stdout (default: tmp/output.<ext>)")
+	formatName := fs.String("format", "markdown", "output format: markdown, xml, jsonl, or plain")
+	tokenizerName := fs.String("tokenizer", "heuristic", "tokenizer: heuristic, cl100k_base, or o200k_base")
+	maxTokens := fs.Int("max-tokens", 0, "drop the largest files until the total fits this token budget (0 = unlimited)")
+	maxBytes := fs.Int64("max-bytes", 0, "skip any file larger than this many bytes (0 = unlimited)")
+	concurrency := fs.Int("concurrency", 0, "number of worker goroutines for file processing (0 = GOMAXPROCS)")
+	failOnSecret := fs.Bool("fail-on-secret", false, "exit nonzero if any secret is redacted")
+	followSymlinks := fs.Bool("follow-symlinks", false, "descend into symlinked directories")
+	rev := fs.String("rev", "", "snapshot this git revision instead of walking the working tree")
+	diff := fs.String("diff", "", "emit only files changed between base..head, as unified diffs (overrides --rev)")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	rootDir := *root
+	if rootDir == "" {
+		var err error
+		rootDir, err = os.Getwd()
+		if err != nil {
+			return fmt.Errorf("failed to get current directory: %v", err)
+		}
+	}
+
+	if *patternsFile != "" {
+		filePatterns, err := readRegexPatternsFromFile(filepath.Join(rootDir, *patternsFile))
+		if err != nil {
+			return fmt.Errorf("failed to read --patterns-file: %v", err)
+		}
+		include.patterns = append(include.patterns, filePatterns...)
+	}
+
+	opts := GenerateOptions{
+		RootDir:        rootDir,
+		Include:        include.patterns,
+		Exclude:        exclude.patterns,
+		Format:         *formatName,
+		Tokenizer:      *tokenizerName,
+		MaxTokens:      *maxTokens,
+		MaxBytes:       *maxBytes,
+		Concurrency:    *concurrency,
+		FailOnSecret:   *failOnSecret,
+		FollowSymlinks: *followSymlinks,
+		Rev:            *rev,
+		Diff:           *diff,
+	}
+
+	out, closeOut, err := openOutput(*output, rootDir, opts.Format)
+	if err != nil {
+		return err
+	}
+	defer closeOut()
+
+	return generateProjectSummary(out, opts)
+}
+
+// openOutput resolves --output into a writer: "-" means stdout, an empty
+// value falls back to tmp/output.<ext> (matching the tool's historical
+// default), and anything else is opened as a path. The returned close
+// func is always safe to call, even for stdout.
+func openOutput(output, rootDir, formatName string) (*os.File, func(), error) {
+	var path string
+	switch output {
+	case "-":
+		return os.Stdout, func() {}, nil
+	case "":
+		path = filepath.Join(rootDir, "tmp/output"+format.Extension(formatName))
+	default:
+		path = output
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, nil, fmt.Errorf("failed to create output directory: %v", err)
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create output file: %v", err)
+	}
+	return f, func() { f.Close() }, nil
+}
+
+// cmdWizard is the original prompt-driven flow, kept for users who'd
+// rather answer two questions than remember flags.
+func cmdWizard() {
+	var rootDir string
+	fmt.Print("Enter the root directory path (leave blank for current directory): ")
+	fmt.Scanln(&rootDir)
+	if rootDir == "" {
+		var err error
+		rootDir, err = os.Getwd()
+		if err != nil {
+			fmt.Println("Error getting current directory:", err)
+			return
+		}
+	}
+
+	var option string
+	fmt.Print("Enter 'all' to process all files, or provide a filepath for regex patterns: ")
+	fmt.Scanln(&option)
+
+	var include []*regexp.Regexp
+	if option != "all" && option != "" {
+		var err error
+		include, err = readRegexPatternsFromFile(filepath.Join(rootDir, option))
+		if err != nil {
+			fmt.Printf("Error reading regex patterns: %v\n", err)
+			return
+		}
+	}
+
+	out, closeOut, err := openOutput("", rootDir, "markdown")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer closeOut()
+
+	opts := GenerateOptions{
+		RootDir:   rootDir,
+		Include:   include,
+		Format:    "markdown",
+		Tokenizer: "heuristic",
+	}
+	if err := generateProjectSummary(out, opts); err != nil {
+		fmt.Println(err)
+	}
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCmdGenerateWritesFileContentToOutput(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "hello.go"), []byte("package hello\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	output := filepath.Join(root, "out.md")
+	if err := cmdGenerate([]string{"--root", root, "--output", output}); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(got), "package hello") {
+		t.Errorf("output = %q, want it to contain the source file's content", got)
+	}
+}
+
+func TestCmdGenerateExcludeDoesNotDropEverything(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "keep.txt"), []byte("keep me"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "drop.txt"), []byte("drop me"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	output := filepath.Join(root, "out.md")
+	if err := cmdGenerate([]string{"--root", root, "--exclude", "drop\\.txt", "--output", output}); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(got), "keep me") {
+		t.Errorf("output = %q, want the non-excluded file's content", got)
+	}
+	if strings.Contains(string(got), "drop me") {
+		t.Errorf("output = %q, want the excluded file's content to be absent", got)
+	}
+}
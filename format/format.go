@@ -0,0 +1,113 @@
+// Package format renders a captured project summary (directory structure
+// plus file contents) into one of several output formats suited to
+// different LLM ingestion pipelines.
+package format
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+// Formatter emits a project summary to w. Callers invoke WriteHeader once,
+// WriteStructure once with the marshaled directory structure, WriteFile
+// once per included file, and WriteFooter once at the end.
+type Formatter interface {
+	WriteHeader(w io.Writer) error
+	WriteStructure(w io.Writer, structureJSON []byte) error
+	WriteFile(w io.Writer, relPath, content, lang string, tokens int) error
+	WriteFooter(w io.Writer, stats Stats) error
+}
+
+// FileTokens is one row of the "largest files by tokens" report.
+type FileTokens struct {
+	Path   string `json:"path"`
+	Tokens int    `json:"tokens"`
+}
+
+// Stats summarizes token usage across a whole run, written by WriteFooter.
+type Stats struct {
+	TotalTokens int          `json:"total_tokens"`
+	TopFiles    []FileTokens `json:"top_files_by_tokens,omitempty"`
+}
+
+// New returns the Formatter registered under name.
+func New(name string) (Formatter, error) {
+	switch name {
+	case "", "markdown", "md":
+		return markdownFormatter{}, nil
+	case "xml":
+		return xmlFormatter{}, nil
+	case "jsonl":
+		return jsonlFormatter{}, nil
+	case "plain", "text", "txt":
+		return plainFormatter{}, nil
+	default:
+		return nil, fmt.Errorf("unknown format %q", name)
+	}
+}
+
+// Extension returns the conventional file extension (including the dot)
+// for the named format, used to pick a default output path.
+func Extension(name string) string {
+	switch name {
+	case "xml":
+		return ".xml"
+	case "jsonl":
+		return ".jsonl"
+	case "plain", "text", "txt":
+		return ".txt"
+	default:
+		return ".md"
+	}
+}
+
+// LangForPath guesses a Markdown/syntax-highlighting language tag from a
+// file's extension, falling back to "" (no tag) when unknown.
+func LangForPath(relPath string) string {
+	switch strings.ToLower(filepath.Ext(relPath)) {
+	case ".go":
+		return "go"
+	case ".py":
+		return "python"
+	case ".js":
+		return "javascript"
+	case ".jsx":
+		return "jsx"
+	case ".ts":
+		return "typescript"
+	case ".tsx":
+		return "tsx"
+	case ".rs":
+		return "rust"
+	case ".java":
+		return "java"
+	case ".rb":
+		return "ruby"
+	case ".sh", ".bash":
+		return "bash"
+	case ".json":
+		return "json"
+	case ".yaml", ".yml":
+		return "yaml"
+	case ".toml":
+		return "toml"
+	case ".md":
+		return "markdown"
+	case ".html":
+		return "html"
+	case ".css":
+		return "css"
+	case ".sql":
+		return "sql"
+	case ".c":
+		return "c"
+	case ".h":
+		return "c"
+	case ".cpp", ".cc", ".hpp":
+		return "cpp"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,61 @@
+package format
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// jsonlFormatter emits one JSON object per line, one line per file, so
+// the output can be streamed into an LLM ingestion pipeline without
+// buffering the whole document.
+type jsonlFormatter struct{}
+
+type jsonlStructureLine struct {
+	Type      string          `json:"type"`
+	Structure json.RawMessage `json:"structure"`
+}
+
+type jsonlFileLine struct {
+	Type   string `json:"type"`
+	Path   string `json:"path"`
+	Lang   string `json:"lang,omitempty"`
+	Tokens int    `json:"tokens"`
+	Text   string `json:"content"`
+}
+
+type jsonlSummaryLine struct {
+	Type string `json:"type"`
+	Stats
+}
+
+func (jsonlFormatter) WriteHeader(w io.Writer) error {
+	return nil
+}
+
+func (jsonlFormatter) WriteStructure(w io.Writer, structureJSON []byte) error {
+	line, err := json.Marshal(jsonlStructureLine{Type: "structure", Structure: structureJSON})
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "%s\n", line)
+	return err
+}
+
+func (jsonlFormatter) WriteFile(w io.Writer, relPath, content, lang string, tokens int) error {
+	line, err := json.Marshal(jsonlFileLine{Type: "file", Path: relPath, Lang: lang, Tokens: tokens, Text: content})
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "%s\n", line)
+	return err
+}
+
+func (jsonlFormatter) WriteFooter(w io.Writer, stats Stats) error {
+	line, err := json.Marshal(jsonlSummaryLine{Type: "summary", Stats: stats})
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "%s\n", line)
+	return err
+}
@@ -0,0 +1,60 @@
+package format
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestJSONLFormatterRoundTrips(t *testing.T) {
+	var buf bytes.Buffer
+	f := jsonlFormatter{}
+
+	if err := f.WriteStructure(&buf, []byte(`{"":{"files":["a.go"]}}`)); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.WriteFile(&buf, "a.go", "package a\n", "go", 3); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.WriteFooter(&buf, Stats{TotalTokens: 3, TopFiles: []FileTokens{{Path: "a.go", Tokens: 3}}}); err != nil {
+		t.Fatal(err)
+	}
+
+	var lines []map[string]json.RawMessage
+	dec := json.NewDecoder(&buf)
+	for dec.More() {
+		var line map[string]json.RawMessage
+		if err := dec.Decode(&line); err != nil {
+			t.Fatalf("line %d isn't valid JSON: %v", len(lines), err)
+		}
+		lines = append(lines, line)
+	}
+	if len(lines) != 3 {
+		t.Fatalf("got %d lines, want 3 (structure, file, summary)", len(lines))
+	}
+
+	var fileLine jsonlFileLine
+	if err := json.Unmarshal(mustMarshal(t, lines[1]), &fileLine); err != nil {
+		t.Fatal(err)
+	}
+	if fileLine.Type != "file" || fileLine.Path != "a.go" || fileLine.Text != "package a\n" || fileLine.Tokens != 3 {
+		t.Errorf("file line = %+v, want type=file path=a.go content=%q tokens=3", fileLine, "package a\n")
+	}
+
+	var summaryLine jsonlSummaryLine
+	if err := json.Unmarshal(mustMarshal(t, lines[2]), &summaryLine); err != nil {
+		t.Fatal(err)
+	}
+	if summaryLine.Type != "summary" || summaryLine.TotalTokens != 3 {
+		t.Errorf("summary line = %+v, want type=summary total_tokens=3", summaryLine)
+	}
+}
+
+func mustMarshal(t *testing.T, v any) []byte {
+	t.Helper()
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
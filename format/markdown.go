@@ -0,0 +1,69 @@
+package format
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// markdownFormatter reproduces the original fenced-code-block layout, but
+// widens the fence to avoid clashing with backticks already present in
+// the file's own content (the same trick repomix and files-to-prompt use).
+type markdownFormatter struct{}
+
+func (markdownFormatter) WriteHeader(w io.Writer) error {
+	return nil
+}
+
+func (markdownFormatter) WriteStructure(w io.Writer, structureJSON []byte) error {
+	_, err := fmt.Fprintf(w, "## Project Structure\n```json\n%s\n```\n\n## File Contents\n\n", structureJSON)
+	return err
+}
+
+func (markdownFormatter) WriteFile(w io.Writer, relPath, content, lang string, tokens int) error {
+	fence := fenceFor(content)
+	_, err := fmt.Fprintf(w, "### %s (%d tokens)\n%s%s\n%s\n%s\n", relPath, tokens, fence, lang, content, fence)
+	return err
+}
+
+func (markdownFormatter) WriteFooter(w io.Writer, stats Stats) error {
+	_, err := fmt.Fprintf(w, "## Summary\n\nTotal tokens: %d\n\n%s", stats.TotalTokens, topFilesTable(stats.TopFiles))
+	return err
+}
+
+// topFilesTable renders the largest-files-by-tokens report as a Markdown
+// table, or an empty string when there's nothing to report.
+func topFilesTable(top []FileTokens) string {
+	if len(top) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("### Largest files by tokens\n\n| File | Tokens |\n| --- | --- |\n")
+	for _, f := range top {
+		fmt.Fprintf(&b, "| %s | %d |\n", f.Path, f.Tokens)
+	}
+	return b.String()
+}
+
+// fenceFor returns a backtick fence at least one backtick longer than the
+// longest run of backticks found in content, with a minimum width of 3.
+func fenceFor(content string) string {
+	longest := 0
+	run := 0
+	for _, r := range content {
+		if r == '`' {
+			run++
+			if run > longest {
+				longest = run
+			}
+		} else {
+			run = 0
+		}
+	}
+
+	width := longest + 1
+	if width < 3 {
+		width = 3
+	}
+	return strings.Repeat("`", width)
+}
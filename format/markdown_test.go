@@ -0,0 +1,20 @@
+package format
+
+import "testing"
+
+func TestFenceForWidensPastEmbeddedBackticks(t *testing.T) {
+	cases := []struct {
+		content string
+		want    int
+	}{
+		{"no backticks here", 3},
+		{"some `inline code`", 3},
+		{"a fenced block:\n```\ncode\n```\n", 4},
+		{"nested fences:\n````\n```\ninner\n```\n````\n", 5},
+	}
+	for _, c := range cases {
+		if got := len(fenceFor(c.content)); got != c.want {
+			t.Errorf("fenceFor(%q) width = %d, want %d", c.content, got, c.want)
+		}
+	}
+}
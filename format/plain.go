@@ -0,0 +1,29 @@
+package format
+
+import (
+	"fmt"
+	"io"
+)
+
+// plainFormatter concatenates file contents with a minimal path header
+// and no fencing at all, for tools that just want raw text.
+type plainFormatter struct{}
+
+func (plainFormatter) WriteHeader(w io.Writer) error {
+	return nil
+}
+
+func (plainFormatter) WriteStructure(w io.Writer, structureJSON []byte) error {
+	_, err := fmt.Fprintf(w, "Project Structure\n%s\n\n", structureJSON)
+	return err
+}
+
+func (plainFormatter) WriteFile(w io.Writer, relPath, content, lang string, tokens int) error {
+	_, err := fmt.Fprintf(w, "=== %s (%d tokens) ===\n%s\n\n", relPath, tokens, content)
+	return err
+}
+
+func (plainFormatter) WriteFooter(w io.Writer, stats Stats) error {
+	_, err := fmt.Fprintf(w, "Total tokens: %d\n", stats.TotalTokens)
+	return err
+}
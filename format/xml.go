@@ -0,0 +1,62 @@
+package format
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// xmlFormatter wraps the structure and each file in tags of the shape
+// Claude's own docs recommend for long-context prompts:
+// <file path="...">...</file>.
+type xmlFormatter struct{}
+
+func (xmlFormatter) WriteHeader(w io.Writer) error {
+	_, err := fmt.Fprint(w, "<project>\n")
+	return err
+}
+
+func (xmlFormatter) WriteStructure(w io.Writer, structureJSON []byte) error {
+	var escaped bytes.Buffer
+	if err := xml.EscapeText(&escaped, structureJSON); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(w, "  <structure>\n%s\n  </structure>\n", escaped.String())
+	return err
+}
+
+func (xmlFormatter) WriteFile(w io.Writer, relPath, content, lang string, tokens int) error {
+	_, err := fmt.Fprintf(w, "  <file path=\"%s\" tokens=\"%d\">\n%s\n  </file>\n", escapeAttr(relPath), tokens, escapeCDATA(content))
+	return err
+}
+
+func (xmlFormatter) WriteFooter(w io.Writer, stats Stats) error {
+	if _, err := fmt.Fprintf(w, "  <summary total_tokens=\"%d\">\n", stats.TotalTokens); err != nil {
+		return err
+	}
+	for _, f := range stats.TopFiles {
+		if _, err := fmt.Fprintf(w, "    <top_file path=\"%s\" tokens=\"%d\"/>\n", escapeAttr(f.Path), f.Tokens); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprint(w, "  </summary>\n</project>\n")
+	return err
+}
+
+// escapeCDATA wraps content in a CDATA section, splitting on any "]]>"
+// sequence the content itself contains so the section can't be closed
+// early.
+func escapeCDATA(content string) string {
+	return "<![CDATA[" + strings.ReplaceAll(content, "]]>", "]]]]><![CDATA[>") + "]]>"
+}
+
+// escapeAttr escapes relPath for use inside a double-quoted XML
+// attribute (path=%q uses Go string escaping, not XML's, and produces
+// invalid XML for paths containing a literal `"`).
+func escapeAttr(s string) string {
+	var escaped bytes.Buffer
+	xml.EscapeText(&escaped, []byte(s))
+	return escaped.String()
+}
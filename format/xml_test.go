@@ -0,0 +1,41 @@
+package format
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+func TestXMLFormatterEscapesQuotesInPath(t *testing.T) {
+	var buf bytes.Buffer
+	f := xmlFormatter{}
+	if err := f.WriteFile(&buf, `foo"bar.txt`, "hello", "", 2); err != nil {
+		t.Fatal(err)
+	}
+
+	wrapped := "<root>\n" + buf.String() + "</root>"
+	var v struct {
+		XMLName xml.Name `xml:"root"`
+		File    struct {
+			Path string `xml:"path,attr"`
+		} `xml:"file"`
+	}
+	if err := xml.Unmarshal([]byte(wrapped), &v); err != nil {
+		t.Fatalf("output isn't valid XML: %v\n%s", err, wrapped)
+	}
+	if v.File.Path != `foo"bar.txt` {
+		t.Errorf("path attr round-tripped to %q, want %q", v.File.Path, `foo"bar.txt`)
+	}
+}
+
+func TestXMLFormatterSplitsEmbeddedCDATAClose(t *testing.T) {
+	var buf bytes.Buffer
+	f := xmlFormatter{}
+	if err := f.WriteFile(&buf, "weird.txt", "before]]>after", "", 1); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(buf.String(), "]]>after") {
+		t.Errorf("content's ]]> wasn't split, CDATA section closed early:\n%s", buf.String())
+	}
+}
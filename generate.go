@@ -5,15 +5,20 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"io/fs"
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
 
-	ignore "github.com/sabhiram/go-gitignore"
+	"github.com/timtoronto634/doc-input-gen/format"
+	"github.com/timtoronto634/doc-input-gen/ignore"
+	"github.com/timtoronto634/doc-input-gen/redact"
+	"github.com/timtoronto634/doc-input-gen/tokenizer"
 )
 
+// predefinedIgnores are the default ignore patterns applied even when a
+// caller doesn't supply their own via ignore.WithDefaults.
 var predefinedIgnores = []string{
 	".git",
 	".vscode/",
@@ -46,25 +51,12 @@ func isBinary(filePath string) bool {
 	return false
 }
 
-func shouldIgnore(path string, gitIgnore, summaryIgnore *ignore.GitIgnore) bool {
-	// Check predefined ignores
-	for _, ignorePath := range predefinedIgnores {
-		if strings.HasPrefix(path, ignorePath) {
-			return true
-		}
-	}
-
-	// Check .gitignore and .summaryignore patterns
-	return (gitIgnore != nil && gitIgnore.MatchesPath(path)) ||
-		(summaryIgnore != nil && summaryIgnore.MatchesPath(path))
-}
-
 type DirectoryStructure struct {
 	Directories []string `json:"directories"`
 	Files       []string `json:"files"`
 }
 
-func captureDirectoryStructure(rootDir string, gitIgnore, summaryIgnore *ignore.GitIgnore) map[string]DirectoryStructure {
+func captureDirectoryStructure(rootDir string, ignorer ignore.Ignorer) map[string]DirectoryStructure {
 	structure := make(map[string]DirectoryStructure)
 
 	filepath.Walk(rootDir, func(path string, info os.FileInfo, err error) error {
@@ -81,7 +73,7 @@ func captureDirectoryStructure(rootDir string, gitIgnore, summaryIgnore *ignore.
 			relPath = ""
 		}
 
-		if shouldIgnore(relPath, gitIgnore, summaryIgnore) {
+		if ignorer.Match(relPath, info.IsDir()) {
 			if info.IsDir() {
 				return filepath.SkipDir
 			}
@@ -107,108 +99,214 @@ func captureDirectoryStructure(rootDir string, gitIgnore, summaryIgnore *ignore.
 	return structure
 }
 
-func generateProjectSummary(rootDir string, regexPatterns []*regexp.Regexp) {
-	gitIgnore, err := ignore.CompileIgnoreFile(filepath.Join(rootDir, ".gitignore"))
-	if err != nil && !os.IsNotExist(err) {
-		fmt.Printf("failed to compile .gitignore: %v\n", err)
-		return
-	}
+// topFilesReported is how many rows appear in the "largest files by
+// tokens" table.
+const topFilesReported = 10
+
+// candidateFile is a file that survived ignore/regex/binary filtering,
+// read into memory along with its estimated token count.
+type candidateFile struct {
+	relPath string
+	content string
+	tokens  int
+	secrets []redact.Match
+}
 
-	summaryIgnore, err := ignore.CompileIgnoreFile(filepath.Join(rootDir, ".summaryignore"))
-	if err != nil && !os.IsNotExist(err) {
-		fmt.Printf("failed to compile .summaryignore: %v\n", err)
-		return
-	}
+// GenerateOptions controls a single generateProjectSummary run. It's the
+// shared surface between the non-interactive `docgen generate` command
+// and the interactive `docgen wizard` fallback.
+type GenerateOptions struct {
+	RootDir        string
+	Include        []*regexp.Regexp
+	Exclude        []*regexp.Regexp
+	Format         string
+	Tokenizer      string
+	MaxTokens      int
+	MaxBytes       int64
+	Concurrency    int
+	FailOnSecret   bool
+	FollowSymlinks bool
+
+	// Rev, when set, snapshots RootDir's repository at this revision
+	// (a ref, tag, or anything go-git's revision syntax accepts)
+	// instead of walking the working tree.
+	Rev string
+	// Diff, when set as "base..head", emits only the files that
+	// changed between the two revisions, each as a unified diff,
+	// instead of full file contents. Rev is ignored when Diff is set.
+	Diff string
+}
 
-	outputFile := filepath.Join(rootDir, "tmp/output.txt")
-	outFile, err := os.Create(outputFile)
+// generateProjectSummary captures rootDir's structure and file contents
+// per opts and writes the rendered summary to w. It returns an error
+// instead of printing and returning early, so callers (CLI or otherwise)
+// decide how to report failure.
+func generateProjectSummary(w io.Writer, opts GenerateOptions) error {
+	ignorer, err := ignore.NewIgnorer(opts.RootDir, ignore.WithDefaults(predefinedIgnores))
 	if err != nil {
-		fmt.Printf("failed to create output file: %v", err)
-		return
+		return fmt.Errorf("failed to build ignorer: %v", err)
 	}
-	defer outFile.Close()
 
-	// Capture and write project structure
-	structure := captureDirectoryStructure(rootDir, gitIgnore, summaryIgnore)
-	structureJSON, err := json.MarshalIndent(structure, "", "  ")
+	formatter, err := format.New(opts.Format)
 	if err != nil {
-		fmt.Printf("failed to marshal project structure: %v", err)
-		return
+		return fmt.Errorf("failed to select formatter: %v", err)
 	}
 
-	_, err = fmt.Fprintf(outFile, "## Project Structure\n```json\n%s\n```\n\n", structureJSON)
+	tok, err := tokenizer.New(opts.Tokenizer)
 	if err != nil {
-		fmt.Printf("failed to write project structure: %v", err)
-		return
+		return fmt.Errorf("failed to select tokenizer: %v", err)
 	}
 
-	_, err = fmt.Fprint(outFile, "## File Contents\n\n")
+	redactor, err := redact.New(filepath.Join(opts.RootDir, ".summaryredact"))
 	if err != nil {
-		fmt.Printf("failed to write to output file: %v", err)
-		return
+		return fmt.Errorf("failed to build redactor: %v", err)
 	}
 
-	processFile := func(path string) error {
-		relPath, err := filepath.Rel(rootDir, path)
+	if err := formatter.WriteHeader(w); err != nil {
+		return fmt.Errorf("failed to write header: %v", err)
+	}
+
+	var structure map[string]DirectoryStructure
+	var candidates []candidateFile
+
+	switch {
+	case opts.Diff != "":
+		candidates, err = collectDiffCandidates(opts.RootDir, opts.Diff, opts, tok, redactor)
 		if err != nil {
-			return fmt.Errorf("failed to get relative path: %v", err)
+			return fmt.Errorf("failed to process diff: %v", err)
 		}
+		structure = diffStructure(candidates)
 
-		if shouldIgnore(relPath, gitIgnore, summaryIgnore) {
-			fmt.Printf("Ignoring %s\n", relPath)
-			return nil
+	case opts.Rev != "":
+		tree, err := resolveTree(opts.RootDir, opts.Rev)
+		if err != nil {
+			return err
 		}
-
-		if isBinary(path) {
-			fmt.Printf("Ignoring binary file %s\n", relPath)
-			return nil
+		structure, err = captureDirectoryStructureFromTree(tree)
+		if err != nil {
+			return fmt.Errorf("failed to capture project structure: %v", err)
 		}
-
-		content, err := os.ReadFile(path)
+		candidates, err = collectCandidatesFromTree(tree, opts, tok, redactor)
 		if err != nil {
-			return fmt.Errorf("failed to read file %s: %v", path, err)
+			return fmt.Errorf("failed to process files: %v", err)
 		}
 
-		_, err = fmt.Fprintf(outFile, "### %s\n```\n%s\n```\n", relPath, content)
+	default:
+		structure = captureDirectoryStructure(opts.RootDir, ignorer)
+		candidates, err = collectCandidates(opts, ignorer, tok, redactor)
 		if err != nil {
-			return fmt.Errorf("failed to write to output file: %v", err)
+			return fmt.Errorf("failed to process files: %v", err)
 		}
+	}
 
-		return nil
+	structureJSON, err := json.MarshalIndent(structure, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal project structure: %v", err)
 	}
 
-	err = filepath.WalkDir(rootDir, func(path string, d fs.DirEntry, err error) error {
-		if err != nil {
-			return err
-		}
+	if err := formatter.WriteStructure(w, structureJSON); err != nil {
+		return fmt.Errorf("failed to write project structure: %v", err)
+	}
 
-		if d.IsDir() {
-			return nil
-		}
+	if secretsFound := reportSecrets(candidates); secretsFound && opts.FailOnSecret {
+		return fmt.Errorf("aborting: secrets found and --fail-on-secret is set")
+	}
 
-		relPath, err := filepath.Rel(rootDir, path)
-		if err != nil {
-			return fmt.Errorf("failed to get relative path: %v", err)
-		}
+	stats := buildStats(candidates)
+
+	included := candidates
+	if opts.MaxTokens > 0 {
+		included = enforceTokenBudget(candidates, opts.MaxTokens)
+	}
 
-		if len(regexPatterns) == 0 {
-			// Process all files if no patterns are specified
-			return processFile(path)
+	for _, f := range included {
+		if err := formatter.WriteFile(w, f.relPath, f.content, format.LangForPath(f.relPath), f.tokens); err != nil {
+			return fmt.Errorf("failed to write to output file: %v", err)
 		}
+	}
 
-		for _, pattern := range regexPatterns {
-			if pattern.MatchString(relPath) {
-				return processFile(path)
-			}
+	fmt.Fprintf(os.Stderr, "Total tokens: %d\n", stats.TotalTokens)
+
+	if err := formatter.WriteFooter(w, stats); err != nil {
+		return fmt.Errorf("failed to write footer: %v", err)
+	}
+	return nil
+}
+
+// reportSecrets prints a line for every redacted secret found across
+// candidates and reports whether any were found at all.
+func reportSecrets(candidates []candidateFile) bool {
+	found := false
+	for _, f := range candidates {
+		for _, m := range f.secrets {
+			found = true
+			fmt.Fprintf(os.Stderr, "Redacted %d %s secret(s) in %s\n", m.Count, m.Kind, f.relPath)
 		}
+	}
+	return found
+}
 
-		return nil
+// buildStats computes the grand total token count and the top N largest
+// files by tokens across every candidate, regardless of whether a
+// --max-tokens budget later excludes some of them from the output.
+func buildStats(candidates []candidateFile) format.Stats {
+	stats := format.Stats{}
+	ranked := make([]candidateFile, len(candidates))
+	copy(ranked, candidates)
+	sort.Slice(ranked, func(i, j int) bool {
+		return ranked[i].tokens > ranked[j].tokens
 	})
 
-	if err != nil {
-		fmt.Printf("failed to process files: %v", err)
-		return
+	for _, f := range candidates {
+		stats.TotalTokens += f.tokens
+	}
+
+	n := topFilesReported
+	if n > len(ranked) {
+		n = len(ranked)
+	}
+	for _, f := range ranked[:n] {
+		stats.TopFiles = append(stats.TopFiles, format.FileTokens{Path: f.relPath, Tokens: f.tokens})
+	}
+
+	return stats
+}
+
+// enforceTokenBudget drops the largest remaining files, one at a time,
+// until the total token count of what's left fits within maxTokens. The
+// original walk order of the survivors is preserved for output.
+func enforceTokenBudget(candidates []candidateFile, maxTokens int) []candidateFile {
+	total := 0
+	for _, f := range candidates {
+		total += f.tokens
+	}
+	if total <= maxTokens {
+		return candidates
 	}
+
+	dropped := make(map[string]bool)
+	byTokensDesc := make([]candidateFile, len(candidates))
+	copy(byTokensDesc, candidates)
+	sort.Slice(byTokensDesc, func(i, j int) bool {
+		return byTokensDesc[i].tokens > byTokensDesc[j].tokens
+	})
+
+	for _, f := range byTokensDesc {
+		if total <= maxTokens {
+			break
+		}
+		fmt.Fprintf(os.Stderr, "Dropping %s (%d tokens) to stay within --max-tokens budget\n", f.relPath, f.tokens)
+		dropped[f.relPath] = true
+		total -= f.tokens
+	}
+
+	kept := make([]candidateFile, 0, len(candidates))
+	for _, f := range candidates {
+		if !dropped[f.relPath] {
+			kept = append(kept, f)
+		}
+	}
+	return kept
 }
 
 func readRegexPatternsFromFile(filePath string) ([]*regexp.Regexp, error) {
@@ -237,33 +335,3 @@ func readRegexPatternsFromFile(filePath string) ([]*regexp.Regexp, error) {
 
 	return patterns, nil
 }
-
-func generate() {
-	var rootDir string
-	fmt.Print("Enter the root directory path (leave blank for current directory): ")
-	fmt.Scanln(&rootDir)
-	if rootDir == "" {
-		var err error
-		rootDir, err = os.Getwd()
-		if err != nil {
-			fmt.Println("Error getting current directory:", err)
-			return
-		}
-	}
-
-	var option string
-	fmt.Print("Enter 'all' to process all files, or provide a filepath for regex patterns: ")
-	fmt.Scanln(&option)
-
-	var regexPatterns []*regexp.Regexp
-	var err error
-	if option != "all" && option != "" {
-		regexPatterns, err = readRegexPatternsFromFile(filepath.Join(rootDir, option))
-		if err != nil {
-			fmt.Printf("Error reading regex patterns: %v\n", err)
-			return
-		}
-	}
-
-	generateProjectSummary(rootDir, regexPatterns)
-}
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+
+	"github.com/timtoronto634/doc-input-gen/redact"
+	"github.com/timtoronto634/doc-input-gen/tokenizer"
+)
+
+// resolveTree opens the repo at rootDir and resolves rev (a ref, tag, or
+// anything else go-git's revision syntax understands, e.g. "HEAD~5") to
+// its commit tree.
+func resolveTree(rootDir, rev string) (*object.Tree, error) {
+	repo, err := git.PlainOpen(rootDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open git repository at %s: %v", rootDir, err)
+	}
+	return resolveTreeIn(repo, rev)
+}
+
+func resolveTreeIn(repo *git.Repository, rev string) (*object.Tree, error) {
+	hash, err := repo.ResolveRevision(plumbing.Revision(rev))
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve revision %q: %v", rev, err)
+	}
+	commit, err := repo.CommitObject(*hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load commit %s: %v", hash, err)
+	}
+	return commit.Tree()
+}
+
+// collectCandidatesFromTree reads every blob in tree that passes
+// opts.Include/opts.Exclude/opts.MaxBytes/binary filtering, in the
+// tree's own (lexical) order.
+func collectCandidatesFromTree(tree *object.Tree, opts GenerateOptions, tok tokenizer.Tokenizer, redactor *redact.Redactor) ([]candidateFile, error) {
+	var candidates []candidateFile
+
+	err := tree.Files().ForEach(func(f *object.File) error {
+		if !wanted(opts, f.Name) {
+			return nil
+		}
+
+		isBinary, err := f.IsBinary()
+		if err != nil {
+			return fmt.Errorf("failed to inspect %s: %v", f.Name, err)
+		}
+		if isBinary {
+			return nil
+		}
+
+		if opts.MaxBytes > 0 && f.Size > opts.MaxBytes {
+			return nil
+		}
+
+		content, err := f.Contents()
+		if err != nil {
+			return fmt.Errorf("failed to read %s at %s: %v", f.Name, tree.Hash, err)
+		}
+
+		text, secrets := redactor.Redact(content)
+		candidates = append(candidates, candidateFile{
+			relPath: f.Name,
+			content: text,
+			tokens:  tok.Count(text),
+			secrets: secrets,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return candidates, nil
+}
+
+// captureDirectoryStructureFromTree mirrors captureDirectoryStructure's
+// output shape, but sourced from a git tree instead of the filesystem.
+func captureDirectoryStructureFromTree(tree *object.Tree) (map[string]DirectoryStructure, error) {
+	structure := make(map[string]DirectoryStructure)
+	knownDirs := make(map[string]bool)
+
+	registerDir := func(dir string) {
+		if dir == "." {
+			dir = ""
+		}
+		for d := dir; d != "" && !knownDirs[d]; d = parentOf(d) {
+			knownDirs[d] = true
+			parent := parentOf(d)
+			entry := structure[parent]
+			entry.Directories = append(entry.Directories, path.Base(d))
+			structure[parent] = entry
+		}
+	}
+
+	err := tree.Files().ForEach(func(f *object.File) error {
+		dir := path.Dir(f.Name)
+		if dir == "." {
+			dir = ""
+		}
+		registerDir(dir)
+
+		entry := structure[dir]
+		entry.Files = append(entry.Files, path.Base(f.Name))
+		structure[dir] = entry
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return structure, nil
+}
+
+func parentOf(dir string) string {
+	if dir == "" {
+		return ""
+	}
+	p := path.Dir(dir)
+	if p == "." {
+		return ""
+	}
+	return p
+}
+
+// collectDiffCandidates resolves base and head (either side of a
+// --diff base..head spec) and returns one candidateFile per changed path,
+// holding that file's unified diff instead of its full contents.
+func collectDiffCandidates(rootDir, diffSpec string, opts GenerateOptions, tok tokenizer.Tokenizer, redactor *redact.Redactor) ([]candidateFile, error) {
+	base, head, err := parseDiffSpec(diffSpec)
+	if err != nil {
+		return nil, err
+	}
+
+	repo, err := git.PlainOpen(rootDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open git repository at %s: %v", rootDir, err)
+	}
+
+	baseTree, err := resolveTreeIn(repo, base)
+	if err != nil {
+		return nil, err
+	}
+	headTree, err := resolveTreeIn(repo, head)
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := baseTree.Diff(headTree)
+	if err != nil {
+		return nil, fmt.Errorf("failed to diff %s..%s: %v", base, head, err)
+	}
+
+	var candidates []candidateFile
+	for _, change := range changes {
+		name := changeName(change)
+		if !wanted(opts, name) {
+			continue
+		}
+
+		patch, err := change.Patch()
+		if err != nil {
+			return nil, fmt.Errorf("failed to build patch for %s: %v", name, err)
+		}
+
+		text, secrets := redactor.Redact(patch.String())
+		candidates = append(candidates, candidateFile{
+			relPath: name,
+			content: text,
+			tokens:  tok.Count(text),
+			secrets: secrets,
+		})
+	}
+	return candidates, nil
+}
+
+// parseDiffSpec splits "base..head" into its two revisions.
+func parseDiffSpec(spec string) (base, head string, err error) {
+	parts := strings.SplitN(spec, "..", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("invalid --diff spec %q, expected base..head", spec)
+	}
+	return parts[0], parts[1], nil
+}
+
+// diffStructure renders the changed-file list as a DirectoryStructure map
+// so --diff mode's "structure" section still means something: the files
+// that changed, rather than a full project tree.
+func diffStructure(candidates []candidateFile) map[string]DirectoryStructure {
+	var files []string
+	for _, c := range candidates {
+		files = append(files, c.relPath)
+	}
+	return map[string]DirectoryStructure{"": {Files: files}}
+}
+
+// changeName picks the most informative path for a git change: the new
+// path, or the old one for a deletion.
+func changeName(change *object.Change) string {
+	if change.To.Name != "" {
+		return change.To.Name
+	}
+	return change.From.Name
+}
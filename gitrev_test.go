@@ -0,0 +1,109 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/timtoronto634/doc-input-gen/redact"
+	"github.com/timtoronto634/doc-input-gen/tokenizer"
+)
+
+// initTestRepo creates a git repo at dir with an initial commit
+// containing path/content, returning its commit hash.
+func initTestRepo(tb testing.TB, dir string) {
+	tb.Helper()
+	run := func(args ...string) {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			tb.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	run("init", "-q")
+	run("config", "user.email", "test@example.com")
+	run("config", "user.name", "Test")
+}
+
+func commitFile(tb testing.TB, dir, relPath, content string) {
+	tb.Helper()
+	full := filepath.Join(dir, relPath)
+	if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+		tb.Fatal(err)
+	}
+	if err := os.WriteFile(full, []byte(content), 0o644); err != nil {
+		tb.Fatal(err)
+	}
+	for _, args := range [][]string{
+		{"add", relPath},
+		{"commit", "-q", "-m", "commit " + relPath},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			tb.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+}
+
+func testTokAndRedactor(tb testing.TB) (tokenizer.Tokenizer, *redact.Redactor) {
+	tb.Helper()
+	tok, err := tokenizer.New("heuristic")
+	if err != nil {
+		tb.Fatal(err)
+	}
+	redactor, err := redact.New("")
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return tok, redactor
+}
+
+func TestCollectCandidatesFromTreeReadsCommittedContent(t *testing.T) {
+	dir := t.TempDir()
+	initTestRepo(t, dir)
+	commitFile(t, dir, "hello.txt", "hello from the repo\n")
+
+	tree, err := resolveTree(dir, "HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tok, redactor := testTokAndRedactor(t)
+	candidates, err := collectCandidatesFromTree(tree, GenerateOptions{RootDir: dir}, tok, redactor)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(candidates) != 1 {
+		t.Fatalf("got %d candidates, want 1", len(candidates))
+	}
+	if candidates[0].relPath != "hello.txt" {
+		t.Errorf("relPath = %q, want hello.txt", candidates[0].relPath)
+	}
+	if candidates[0].content != "hello from the repo\n" {
+		t.Errorf("content = %q, want the committed file body", candidates[0].content)
+	}
+}
+
+func TestCollectDiffCandidatesIncludesChangedContent(t *testing.T) {
+	dir := t.TempDir()
+	initTestRepo(t, dir)
+	commitFile(t, dir, "notes.txt", "line one\n")
+	commitFile(t, dir, "notes.txt", "line one\nline two\n")
+
+	tok, redactor := testTokAndRedactor(t)
+	candidates, err := collectDiffCandidates(dir, "HEAD~1..HEAD", GenerateOptions{RootDir: dir}, tok, redactor)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(candidates) != 1 {
+		t.Fatalf("got %d candidates, want 1", len(candidates))
+	}
+	if !strings.Contains(candidates[0].content, "line two") {
+		t.Errorf("diff content = %q, want it to contain the added line", candidates[0].content)
+	}
+}
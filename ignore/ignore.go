@@ -0,0 +1,258 @@
+// Package ignore implements git-aware ignore matching for doc-input-gen.
+//
+// Unlike a single top-level .gitignore lookup, it walks a directory tree
+// collecting every .gitignore (and .summaryignore) file it finds, compiles
+// each one relative to the directory it lives in, and applies them in the
+// same order git does: parent patterns first, child patterns layered on
+// top so a deeper .gitignore can override (including re-including via `!`)
+// anything an ancestor excluded.
+package ignore
+
+import (
+	"bufio"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	gitignore "github.com/sabhiram/go-gitignore"
+)
+
+// Ignorer decides whether a path (relative to the root passed to
+// NewIgnorer) should be excluded from processing.
+type Ignorer interface {
+	// Match reports whether path should be ignored. isDir indicates
+	// whether path refers to a directory, since some patterns
+	// (e.g. "foo/") only match directories.
+	Match(path string, isDir bool) bool
+}
+
+// layer is a single .gitignore (or .summaryignore) compiled relative to dir.
+type layer struct {
+	dir     string // slash-separated, relative to the ignorer's root, "" for root
+	compile *gitignore.GitIgnore
+	// touch is compile's patterns with any leading "!" stripped, so
+	// touch.MatchesPath reports whether this layer has ANY opinion
+	// (positive or negated) about a path, independent of how that
+	// opinion nets out. Match uses this to decide whether a layer
+	// should override its ancestors' verdict at all.
+	touch *gitignore.GitIgnore
+}
+
+type ignorer struct {
+	root      string
+	defaults  []string
+	layers    []layer // ordered root -> deepest
+	tracked   map[string]bool
+	hasGitDir bool
+}
+
+// Option configures NewIgnorer.
+type Option func(*ignorer)
+
+// WithDefaults overrides the built-in default ignore patterns (normally
+// predefinedIgnores) that are always applied regardless of any
+// .gitignore/.summaryignore content.
+func WithDefaults(patterns []string) Option {
+	return func(ig *ignorer) {
+		ig.defaults = patterns
+	}
+}
+
+// NewIgnorer builds an Ignorer for the tree rooted at rootDir. It collects
+// every .gitignore and .summaryignore file under rootDir, and, when rootDir
+// is inside a Git working tree, also consults `git ls-files` so that
+// untracked-but-ignored files are excluded even without a matching pattern.
+func NewIgnorer(rootDir string, opts ...Option) (Ignorer, error) {
+	ig := &ignorer{
+		root:     rootDir,
+		defaults: defaultIgnores,
+	}
+	for _, opt := range opts {
+		opt(ig)
+	}
+
+	err := filepath.Walk(rootDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			return nil
+		}
+
+		relDir, err := filepath.Rel(rootDir, path)
+		if err != nil {
+			return err
+		}
+		if relDir == "." {
+			relDir = ""
+		}
+		relDir = filepath.ToSlash(relDir)
+
+		if base := filepath.Base(path); base == ".git" && relDir != "" {
+			return filepath.SkipDir
+		}
+
+		for _, name := range []string{".gitignore", ".summaryignore"} {
+			patternFile := filepath.Join(path, name)
+			lines, err := readIgnoreLines(patternFile)
+			if err != nil {
+				return err
+			}
+			if lines != nil {
+				ig.layers = append(ig.layers, newLayer(relDir, lines))
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if _, statErr := os.Stat(filepath.Join(rootDir, ".git")); statErr == nil {
+		ig.hasGitDir = true
+		if tracked, err := gitTrackedAndUntracked(rootDir); err == nil {
+			ig.tracked = tracked
+		}
+	}
+
+	return ig, nil
+}
+
+// readIgnoreLines reads patternFile's lines if it exists, returning (nil,
+// nil) when the file is absent.
+func readIgnoreLines(patternFile string) ([]string, error) {
+	f, err := os.Open(patternFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if lines == nil {
+		lines = []string{}
+	}
+	return lines, nil
+}
+
+// newLayer compiles lines both normally and with every leading "!"
+// stripped, the latter giving a matcher that answers "does this layer
+// have any opinion about this path at all", regardless of negation.
+func newLayer(dir string, lines []string) layer {
+	touchLines := make([]string, len(lines))
+	for i, line := range lines {
+		touchLines[i] = strings.TrimPrefix(line, "!")
+	}
+	return layer{
+		dir:     dir,
+		compile: gitignore.CompileIgnoreLines(lines...),
+		touch:   gitignore.CompileIgnoreLines(touchLines...),
+	}
+}
+
+// gitTrackedAndUntracked returns the set of paths (relative to rootDir,
+// slash-separated) that `git ls-files --cached --others --exclude-standard`
+// reports, i.e. everything Git would consider "not ignored".
+func gitTrackedAndUntracked(rootDir string) (map[string]bool, error) {
+	cmd := exec.Command("git", "ls-files", "--cached", "--others", "--exclude-standard")
+	cmd.Dir = rootDir
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+
+	set := make(map[string]bool)
+	for _, line := range strings.Split(string(out), "\n") {
+		if line == "" {
+			continue
+		}
+		set[filepath.ToSlash(line)] = true
+		// Every parent directory of a tracked file is implicitly "seen".
+		dir := filepath.ToSlash(filepath.Dir(line))
+		for dir != "." && dir != "/" && dir != "" {
+			set[dir] = true
+			dir = filepath.ToSlash(filepath.Dir(dir))
+		}
+	}
+	return set, nil
+}
+
+func (ig *ignorer) Match(path string, isDir bool) bool {
+	path = filepath.ToSlash(path)
+	if path == "" {
+		return false
+	}
+
+	for _, prefix := range ig.defaults {
+		dirOnly := strings.HasSuffix(prefix, "/")
+		trimmed := strings.TrimSuffix(prefix, "/")
+		if strings.HasPrefix(path, trimmed+"/") {
+			return true
+		}
+		if path == trimmed && (!dirOnly || isDir) {
+			return true
+		}
+	}
+
+	// Apply every layer from root to deepest. Unlike a plain OR across
+	// layers, a deeper layer only overrides its ancestors' verdict when
+	// it actually has an opinion (touch) about the path; if it's silent,
+	// the ancestor's verdict carries through unchanged. This is what
+	// lets a child .gitignore re-include something an ancestor excluded
+	// by repeating the pattern with a leading `!`.
+	ignored := false
+	for _, l := range ig.layers {
+		if l.dir != "" && path != l.dir && !strings.HasPrefix(path, l.dir+"/") {
+			continue
+		}
+
+		rel := path
+		if l.dir != "" {
+			rel = strings.TrimPrefix(path, l.dir+"/")
+		}
+		if rel == "" {
+			continue
+		}
+		if isDir {
+			rel += "/"
+		}
+
+		if !l.touch.MatchesPath(rel) {
+			continue
+		}
+		ignored = l.compile.MatchesPath(rel)
+	}
+	if ignored {
+		return true
+	}
+
+	if ig.hasGitDir && ig.tracked != nil {
+		if _, seen := ig.tracked[path]; !seen && !isDir {
+			return true
+		}
+	}
+
+	return false
+}
+
+var defaultIgnores = []string{
+	".git",
+	".vscode",
+	"node_modules",
+	"vendor",
+	".idea",
+	".gitignore",
+	".summaryignore",
+	"output.txt",
+}
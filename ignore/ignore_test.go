@@ -0,0 +1,73 @@
+package ignore
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMatchChildGitignoreOverridesParent(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, ".gitignore"), "foo/*\n")
+	writeFile(t, filepath.Join(root, "foo", ".gitignore"), "!bar.txt\n")
+
+	ig, err := NewIgnorer(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if ig.Match("foo/bar.txt", false) {
+		t.Error("foo/bar.txt: want not ignored, child .gitignore re-includes it")
+	}
+	if !ig.Match("foo/baz.txt", false) {
+		t.Error("foo/baz.txt: want ignored, only matched by the parent pattern")
+	}
+}
+
+func TestMatchDirOnlyPatternDoesNotMatchSameNamedFile(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, ".gitignore"), "build/\n")
+	writeFile(t, filepath.Join(root, "build"), "")
+
+	ig, err := NewIgnorer(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if ig.Match("build", false) {
+		t.Error("a file named build: want not ignored, build/ only targets the directory")
+	}
+	if !ig.Match("build", true) {
+		t.Error("a directory named build: want ignored by build/")
+	}
+}
+
+func TestMatchDefaultsRespectDirOnlySuffix(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "vendor"), "")
+
+	ig, err := NewIgnorer(root, WithDefaults([]string{"vendor/"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if ig.Match("vendor", false) {
+		t.Error("a file named vendor: want not ignored, vendor/ default only targets the directory")
+	}
+	if !ig.Match("vendor", true) {
+		t.Error("a directory named vendor: want ignored by the vendor/ default")
+	}
+	if !ig.Match("vendor/module.go", false) {
+		t.Error("vendor/module.go: want ignored, it's beneath the ignored directory")
+	}
+}
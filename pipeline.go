@@ -0,0 +1,252 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sync"
+
+	"github.com/timtoronto634/doc-input-gen/ignore"
+	"github.com/timtoronto634/doc-input-gen/redact"
+	"github.com/timtoronto634/doc-input-gen/tokenizer"
+)
+
+// seqPath is a candidate path paired with its position in walk order, so
+// results computed out of order by worker goroutines can be reassembled
+// deterministically.
+type seqPath struct {
+	seq  int
+	path string
+}
+
+type seqResult struct {
+	seq  int
+	file *candidateFile // nil when the path was ignored, binary, or unreadable
+	err  error
+}
+
+// collectCandidates walks opts.RootDir for matching paths, then fans out
+// ignore/binary/read/tokenize work across a pool of opts.Concurrency
+// workers. A single collector goroutine gathers results into a reorder
+// buffer keyed by sequence number so the returned slice is in the same
+// order the walk visited the files, regardless of which worker finished
+// first.
+func collectCandidates(opts GenerateOptions, ignorer ignore.Ignorer, tok tokenizer.Tokenizer, redactor *redact.Redactor) ([]candidateFile, error) {
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = runtime.GOMAXPROCS(0)
+	}
+
+	paths, err := walkMatchingPaths(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	in := make(chan seqPath)
+	out := make(chan seqResult)
+
+	var wg sync.WaitGroup
+	wg.Add(concurrency)
+	for i := 0; i < concurrency; i++ {
+		go func() {
+			defer wg.Done()
+			for sp := range in {
+				out <- processCandidate(sp, opts.RootDir, ignorer, tok, redactor)
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+
+	go func() {
+		defer close(in)
+		for _, sp := range paths {
+			in <- sp
+		}
+	}()
+
+	pending := make(map[int]seqResult, concurrency)
+	results := make([]candidateFile, 0, len(paths))
+	next := 0
+	var firstErr error
+
+	for next < len(paths) {
+		res, ok := <-out
+		if !ok {
+			break
+		}
+		pending[res.seq] = res
+
+		for {
+			res, ok := pending[next]
+			if !ok {
+				break
+			}
+			delete(pending, next)
+			next++
+
+			if res.err != nil && firstErr == nil {
+				firstErr = res.err
+			}
+			if res.file != nil {
+				results = append(results, *res.file)
+			}
+		}
+	}
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return results, nil
+}
+
+// matchesAny reports whether relPath matches any of patterns (or true
+// when patterns is empty, meaning "match everything"). Used for Include,
+// where an absent filter means every path passes.
+func matchesAny(patterns []*regexp.Regexp, relPath string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, pattern := range patterns {
+		if pattern.MatchString(relPath) {
+			return true
+		}
+	}
+	return false
+}
+
+// excluded reports whether relPath matches any of patterns (or false
+// when patterns is empty, meaning "exclude nothing"). Used for Exclude,
+// where an absent filter must not exclude anything.
+func excluded(patterns []*regexp.Regexp, relPath string) bool {
+	for _, pattern := range patterns {
+		if pattern.MatchString(relPath) {
+			return true
+		}
+	}
+	return false
+}
+
+// wanted reports whether relPath passes both opts.Include and
+// opts.Exclude, the combined filter every candidate-collection path
+// (filesystem walk, --rev tree walk, --diff) applies identically.
+func wanted(opts GenerateOptions, relPath string) bool {
+	return matchesAny(opts.Include, relPath) && !excluded(opts.Exclude, relPath)
+}
+
+// walkMatchingPaths walks opts.RootDir, returning every regular file
+// that matches opts.Include (or everything, if Include is empty), isn't
+// matched by opts.Exclude, and fits within opts.MaxBytes, tagged with
+// its walk sequence number. Directory symlinks are only descended into
+// when opts.FollowSymlinks is set; file symlinks are always read through
+// since os.ReadFile follows them transparently.
+func walkMatchingPaths(opts GenerateOptions) ([]seqPath, error) {
+	var paths []seqPath
+	seq := 0
+	visited := make(map[string]bool)
+
+	var walkDir func(dir string) error
+	walkDir = func(dir string) error {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return err
+		}
+
+		for _, entry := range entries {
+			path := filepath.Join(dir, entry.Name())
+
+			if entry.Type()&fs.ModeSymlink != 0 {
+				if !opts.FollowSymlinks {
+					continue
+				}
+				target, err := filepath.EvalSymlinks(path)
+				if err != nil || visited[target] {
+					continue
+				}
+				visited[target] = true
+
+				fi, err := os.Stat(target)
+				if err != nil {
+					continue
+				}
+				if fi.IsDir() {
+					if err := walkDir(path); err != nil {
+						return err
+					}
+					continue
+				}
+				// Falls through to the regular-file handling below;
+				// os.ReadFile will follow the symlink itself.
+			} else if entry.IsDir() {
+				if err := walkDir(path); err != nil {
+					return err
+				}
+				continue
+			}
+
+			relPath, err := filepath.Rel(opts.RootDir, path)
+			if err != nil {
+				return fmt.Errorf("failed to get relative path: %v", err)
+			}
+
+			if !wanted(opts, relPath) {
+				continue
+			}
+
+			if opts.MaxBytes > 0 {
+				info, err := entry.Info()
+				if err == nil && info.Size() > opts.MaxBytes {
+					fmt.Fprintf(os.Stderr, "Skipping %s (%d bytes > --max-bytes)\n", relPath, info.Size())
+					continue
+				}
+			}
+
+			paths = append(paths, seqPath{seq: seq, path: path})
+			seq++
+		}
+		return nil
+	}
+
+	if err := walkDir(opts.RootDir); err != nil {
+		return nil, err
+	}
+	return paths, nil
+}
+
+// processCandidate does the per-file work that used to run serially
+// inside the walk callback: ignore matching, binary detection, reading,
+// redaction, and tokenization.
+func processCandidate(sp seqPath, rootDir string, ignorer ignore.Ignorer, tok tokenizer.Tokenizer, redactor *redact.Redactor) seqResult {
+	relPath, err := filepath.Rel(rootDir, sp.path)
+	if err != nil {
+		return seqResult{seq: sp.seq, err: fmt.Errorf("failed to get relative path: %v", err)}
+	}
+
+	if ignorer.Match(relPath, false) {
+		fmt.Fprintf(os.Stderr, "Ignoring %s\n", relPath)
+		return seqResult{seq: sp.seq}
+	}
+
+	if isBinary(sp.path) {
+		fmt.Fprintf(os.Stderr, "Ignoring binary file %s\n", relPath)
+		return seqResult{seq: sp.seq}
+	}
+
+	content, err := os.ReadFile(sp.path)
+	if err != nil {
+		return seqResult{seq: sp.seq, err: fmt.Errorf("failed to read file %s: %v", sp.path, err)}
+	}
+
+	text, secrets := redactor.Redact(string(content))
+	return seqResult{seq: sp.seq, file: &candidateFile{
+		relPath: relPath,
+		content: text,
+		tokens:  tok.Count(text),
+		secrets: secrets,
+	}}
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/timtoronto634/doc-input-gen/ignore"
+	"github.com/timtoronto634/doc-input-gen/redact"
+	"github.com/timtoronto634/doc-input-gen/tokenizer"
+)
+
+// writeFixture populates dir with n small text files spread across a
+// handful of subdirectories.
+func writeFixture(tb testing.TB, dir string, n int) {
+	tb.Helper()
+	for i := 0; i < n; i++ {
+		sub := filepath.Join(dir, fmt.Sprintf("pkg%d", i%16))
+		if err := os.MkdirAll(sub, 0o755); err != nil {
+			tb.Fatal(err)
+		}
+		path := filepath.Join(sub, fmt.Sprintf("file%d.txt", i))
+		content := fmt.Sprintf("contents of file %d\n", i)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			tb.Fatal(err)
+		}
+	}
+}
+
+func newTestOpts(rootDir string, concurrency int) GenerateOptions {
+	return GenerateOptions{
+		RootDir:     rootDir,
+		Concurrency: concurrency,
+	}
+}
+
+func collect(tb testing.TB, opts GenerateOptions) []candidateFile {
+	tb.Helper()
+	ignorer, err := ignore.NewIgnorer(opts.RootDir, ignore.WithDefaults(predefinedIgnores))
+	if err != nil {
+		tb.Fatal(err)
+	}
+	tok, err := tokenizer.New("heuristic")
+	if err != nil {
+		tb.Fatal(err)
+	}
+	redactor, err := redact.New("")
+	if err != nil {
+		tb.Fatal(err)
+	}
+	candidates, err := collectCandidates(opts, ignorer, tok, redactor)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return candidates
+}
+
+func TestCollectCandidatesOrderIsDeterministicUnderConcurrency(t *testing.T) {
+	root := t.TempDir()
+	writeFixture(t, root, 200)
+
+	serial := collect(t, newTestOpts(root, 1))
+	parallel := collect(t, newTestOpts(root, 8))
+
+	if len(serial) != 200 {
+		t.Fatalf("serial found %d files, want all 200 fixture files", len(serial))
+	}
+	if len(serial) != len(parallel) {
+		t.Fatalf("serial found %d files, concurrency=8 found %d", len(serial), len(parallel))
+	}
+	for i := range serial {
+		if serial[i].relPath != parallel[i].relPath {
+			t.Fatalf("order diverged at index %d: serial=%q concurrency=8=%q", i, serial[i].relPath, parallel[i].relPath)
+		}
+	}
+}
+
+// BenchmarkCollectCandidates compares wall-clock time between a single
+// worker and GOMAXPROCS workers over a few thousand files, demonstrating
+// that the worker pool actually parallelizes the work rather than just
+// adding reordering overhead on top of a serial walk.
+func BenchmarkCollectCandidates(b *testing.B) {
+	root := b.TempDir()
+	writeFixture(b, root, 4000)
+
+	for _, concurrency := range []int{1, 8} {
+		concurrency := concurrency
+		b.Run(fmt.Sprintf("concurrency=%d", concurrency), func(b *testing.B) {
+			opts := newTestOpts(root, concurrency)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				collect(b, opts)
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+// Package redact scans file contents for high-signal secrets (cloud
+// credentials, tokens, private keys) before they get written into an
+// LLM-facing summary, and replaces them with a `<REDACTED:kind>` marker.
+package redact
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"regexp"
+)
+
+// pattern pairs a regexp with the kind name used in its replacement
+// marker.
+type pattern struct {
+	kind string
+	re   *regexp.Regexp
+}
+
+// builtinPatterns covers the secret shapes that show up most often when
+// an entire repository is dumped into a single file.
+var builtinPatterns = []pattern{
+	{"aws_access_key", regexp.MustCompile(`AKIA[0-9A-Z]{16}`)},
+	{"github_token", regexp.MustCompile(`gh[pos]_[0-9A-Za-z]{36}`)},
+	{"slack_token", regexp.MustCompile(`xox[baprs]-[0-9A-Za-z-]{10,}`)},
+	{"google_api_key", regexp.MustCompile(`AIza[0-9A-Za-z_-]{35}`)},
+	{"jwt", regexp.MustCompile(`eyJ[A-Za-z0-9_-]+\.eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+`)},
+	{"private_key", regexp.MustCompile(`(?s)-----BEGIN [A-Z ]*PRIVATE KEY-----.*?-----END [A-Z ]*PRIVATE KEY-----`)},
+	{"generic_secret", regexp.MustCompile(`(?i)\b(password|api_key|apikey|secret|token)\s*[:=]\s*['"]?[^\s'"]{8,}['"]?`)},
+}
+
+// Match records how many times a given kind of secret was found in a
+// single Redact call.
+type Match struct {
+	Kind  string
+	Count int
+}
+
+// Redactor replaces secrets in file content with `<REDACTED:kind>`.
+type Redactor struct {
+	patterns []pattern
+}
+
+// New builds a Redactor from the built-in pattern set plus any extra
+// regexes loaded from patternsFile (the same one-regex-per-line format
+// readRegexPatternsFromFile already uses). An empty patternsFile is
+// ignored; a missing file is not an error.
+func New(patternsFile string) (*Redactor, error) {
+	r := &Redactor{patterns: append([]pattern(nil), builtinPatterns...)}
+
+	if patternsFile == "" {
+		return r, nil
+	}
+
+	extra, err := loadCustomPatterns(patternsFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return r, nil
+		}
+		return nil, err
+	}
+	r.patterns = append(r.patterns, extra...)
+	return r, nil
+}
+
+func loadCustomPatterns(patternsFile string) ([]pattern, error) {
+	file, err := os.Open(patternsFile)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var patterns []pattern
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			continue
+		}
+		re, err := regexp.Compile(line)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compile redact pattern %q: %v", line, err)
+		}
+		patterns = append(patterns, pattern{kind: "custom", re: re})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return patterns, nil
+}
+
+// Redact replaces every secret Redactor recognizes in content with
+// `<REDACTED:kind>`, returning the scrubbed text and a list of what kinds
+// of secrets were found (and how many times).
+func (r *Redactor) Redact(content string) (string, []Match) {
+	var matches []Match
+	for _, p := range r.patterns {
+		count := len(p.re.FindAllStringIndex(content, -1))
+		if count == 0 {
+			continue
+		}
+		marker := fmt.Sprintf("<REDACTED:%s>", p.kind)
+		content = p.re.ReplaceAllString(content, marker)
+		matches = append(matches, Match{Kind: p.kind, Count: count})
+	}
+	return content, matches
+}
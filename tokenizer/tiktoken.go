@@ -0,0 +1,26 @@
+//go:build tiktoken
+
+package tokenizer
+
+import (
+	"fmt"
+
+	"github.com/pkoukk/tiktoken-go"
+)
+
+// tiktokenTokenizer wraps a real BPE encoder for exact token counts.
+type tiktokenTokenizer struct {
+	enc *tiktoken.Tiktoken
+}
+
+func newTiktokenTokenizer(encoding string) (Tokenizer, error) {
+	enc, err := tiktoken.GetEncoding(encoding)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load tiktoken encoding %q: %v", encoding, err)
+	}
+	return tiktokenTokenizer{enc: enc}, nil
+}
+
+func (t tiktokenTokenizer) Count(text string) int {
+	return len(t.enc.Encode(text, nil, nil))
+}
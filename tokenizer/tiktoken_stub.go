@@ -0,0 +1,11 @@
+//go:build !tiktoken
+
+package tokenizer
+
+import "fmt"
+
+// newTiktokenTokenizer is stubbed out by default since tiktoken-go pulls
+// in a BPE vocabulary file; build with -tags tiktoken to enable it.
+func newTiktokenTokenizer(encoding string) (Tokenizer, error) {
+	return nil, fmt.Errorf("tokenizer %q requires building with -tags tiktoken", encoding)
+}
@@ -0,0 +1,13 @@
+//go:build !tiktoken
+
+package tokenizer
+
+import "testing"
+
+func TestNewTiktokenWithoutBuildTagErrors(t *testing.T) {
+	// Without the "tiktoken" build tag, cl100k_base must fail clearly
+	// rather than silently falling back to the heuristic tokenizer.
+	if _, err := New("cl100k_base"); err == nil {
+		t.Error("New(\"cl100k_base\") without -tags tiktoken: want an error, got nil")
+	}
+}
@@ -0,0 +1,42 @@
+// Package tokenizer estimates how many LLM tokens a piece of text will
+// consume, so callers can report budgets without shipping a full model
+// vocabulary.
+package tokenizer
+
+import "fmt"
+
+// Tokenizer counts the tokens in text under some encoding.
+type Tokenizer interface {
+	Count(text string) int
+}
+
+// New returns the Tokenizer registered under name. "heuristic" (the
+// default) is always available; "cl100k_base" and "o200k_base" require
+// building with -tags tiktoken.
+func New(name string) (Tokenizer, error) {
+	switch name {
+	case "", "heuristic":
+		return heuristicTokenizer{}, nil
+	case "cl100k_base", "o200k_base":
+		return newTiktokenTokenizer(name)
+	default:
+		return nil, fmt.Errorf("unknown tokenizer %q", name)
+	}
+}
+
+// heuristicTokenizer estimates one token per ~4 characters, the same
+// rule of thumb Anthropic and OpenAI both publish for quick budgeting.
+type heuristicTokenizer struct{}
+
+const charsPerToken = 4
+
+func (heuristicTokenizer) Count(text string) int {
+	if len(text) == 0 {
+		return 0
+	}
+	n := len(text) / charsPerToken
+	if n == 0 {
+		n = 1
+	}
+	return n
+}
@@ -0,0 +1,36 @@
+package tokenizer
+
+import "testing"
+
+func TestHeuristicTokenizerCount(t *testing.T) {
+	cases := []struct {
+		text string
+		want int
+	}{
+		{"", 0},
+		{"hi", 1},
+		{"12345678", 2},
+		{"123456789012", 3},
+	}
+	for _, c := range cases {
+		if got := (heuristicTokenizer{}).Count(c.text); got != c.want {
+			t.Errorf("Count(%q) = %d, want %d", c.text, got, c.want)
+		}
+	}
+}
+
+func TestNewUnknownTokenizer(t *testing.T) {
+	if _, err := New("not-a-real-tokenizer"); err == nil {
+		t.Error("New with an unknown name: want an error, got nil")
+	}
+}
+
+func TestNewDefaultsToHeuristic(t *testing.T) {
+	tok, err := New("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := tok.(heuristicTokenizer); !ok {
+		t.Errorf("New(\"\") returned %T, want heuristicTokenizer", tok)
+	}
+}